@@ -0,0 +1,94 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chainaware
+
+import (
+	"context"
+
+	"github.com/AnomalyFi/hypersdk/builder"
+	"github.com/AnomalyFi/hypersdk/chain"
+)
+
+// Mempool is the subset of chain.Mempool Builder/Gossiper need to drain
+// newly arrived txs for partitioning and reinsert them in fair-share
+// order before a block is packed or a gossip round is triggered. *vm.VM
+// satisfies it via its Mempool() accessor.
+type Mempool interface {
+	Len(ctx context.Context) int
+	PopMax(ctx context.Context) (*chain.Transaction, bool)
+	Add(ctx context.Context, txs []*chain.Transaction)
+}
+
+// Builder wraps a builder.Builder (builder.NewTime, typically). builder.
+// Builder's Run/Queue/Force/Done are a pure wake-up signal with no tx data
+// in or out, so there's no way to hand it a pre-ordered batch directly;
+// instead, Builder drains the mempool into Scheduler and reinserts the
+// weighted-round-robin result immediately before delegating, so whatever
+// the wrapped builder pops next reflects fair-share ordering rather than
+// raw arrival order.
+type Builder struct {
+	inner     builder.Builder
+	mempool   Mempool
+	scheduler *Scheduler
+
+	// maxBlockBytes bounds how much of the reordered batch Force reinserts
+	// at once, mirroring the cap the real block packer will apply anyway.
+	maxBlockBytes uint64
+}
+
+var _ builder.Builder = (*Builder)(nil)
+
+func NewBuilder(inner builder.Builder, mempool Mempool, scheduler *Scheduler, maxBlockBytes uint64) *Builder {
+	return &Builder{inner: inner, mempool: mempool, scheduler: scheduler, maxBlockBytes: maxBlockBytes}
+}
+
+func (b *Builder) Run() { b.inner.Run() }
+
+// Queue drains whatever the mempool has accumulated since the last drain,
+// re-packs it in weighted round-robin order, and reinserts it immediately
+// — the same as Force — before letting the wrapped builder decide whether
+// to propose now. Pack-and-reinsert can't wait for the next Force: Queue's
+// caller elsewhere in hypersdk (and any other RPC) observes the real
+// mempool's length, and leaving it drained-but-not-reinserted between
+// Queue and Force would make a non-empty backlog look empty and could
+// stall block production under chain-aware mode.
+func (b *Builder) Queue(ctx context.Context) {
+	b.repack(ctx)
+	b.inner.Queue(ctx)
+}
+
+// Force re-packs the scheduler's queues in weighted round-robin order,
+// capped per chain, and reinserts that ordering into the mempool
+// immediately before handing off to the wrapped builder, so the block it
+// proposes draws from fairness-ordered txs rather than raw arrival order.
+func (b *Builder) Force(ctx context.Context) error {
+	b.repack(ctx)
+	return b.inner.Force(ctx)
+}
+
+func (b *Builder) Done() { b.inner.Done() }
+
+// repack drains every currently queued tx out of the mempool into the
+// scheduler's per-chainID queues, packs them in weighted round-robin
+// order, and reinserts that ordering into the mempool — so the mempool
+// never sits emptied-but-not-yet-repacked between a drain and the next
+// caller observing it. Txs that do not fit under maxBlockBytes or their
+// chain's cap stay queued in the scheduler, outside the mempool, until a
+// later repack packs them.
+func (b *Builder) repack(ctx context.Context) {
+	for b.mempool.Len(ctx) > 0 {
+		tx, ok := b.mempool.PopMax(ctx)
+		if !ok {
+			break
+		}
+		b.scheduler.Enqueue(ChainID(tx), tx)
+	}
+	if ordered := b.scheduler.Pack(b.maxBlockBytes); len(ordered) > 0 {
+		b.mempool.Add(ctx, ordered)
+	}
+}
+
+// Scheduler exposes the underlying chain-aware scheduler so callers
+// (metrics, tests) can inspect queue depth directly.
+func (b *Builder) Scheduler() *Scheduler { return b.scheduler }
@@ -0,0 +1,116 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chainaware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AnomalyFi/hypersdk/builder"
+	"github.com/AnomalyFi/hypersdk/chain"
+)
+
+// fakeMempool is a Mempool backed by a plain slice, standing in for
+// *vm.VM's real chain.Mempool so Builder can be exercised without one.
+type fakeMempool struct {
+	txs []*chain.Transaction
+}
+
+func (m *fakeMempool) Len(context.Context) int { return len(m.txs) }
+
+func (m *fakeMempool) PopMax(context.Context) (*chain.Transaction, bool) {
+	if len(m.txs) == 0 {
+		return nil, false
+	}
+	tx := m.txs[0]
+	m.txs = m.txs[1:]
+	return tx, true
+}
+
+func (m *fakeMempool) Add(_ context.Context, txs []*chain.Transaction) {
+	m.txs = append(m.txs, txs...)
+}
+
+// fakeInnerBuilder is a builder.Builder that just counts calls, so tests
+// can assert Builder delegates without needing a real hypersdk builder.
+type fakeInnerBuilder struct {
+	queued, forced int
+}
+
+func (b *fakeInnerBuilder) Run()                  {}
+func (b *fakeInnerBuilder) Queue(context.Context) { b.queued++ }
+func (b *fakeInnerBuilder) Force(context.Context) error {
+	b.forced++
+	return nil
+}
+func (b *fakeInnerBuilder) Done() {}
+
+var _ builder.Builder = (*fakeInnerBuilder)(nil)
+
+func TestBuilderQueueRepacksInsteadOfStallingMempool(t *testing.T) {
+	mempool := &fakeMempool{}
+	mempool.Add(context.Background(), []*chain.Transaction{
+		newTestTx(fakeChainAction{chainID: "a"}),
+		newTestTx(fakeChainAction{chainID: "b"}),
+	})
+	inner := &fakeInnerBuilder{}
+	b := NewBuilder(inner, mempool, NewScheduler(nil, nil), ^uint64(0))
+
+	b.Queue(context.Background())
+
+	if inner.queued != 1 {
+		t.Fatalf("inner.Queue called %d times, want 1", inner.queued)
+	}
+	// The whole point of repack over a bare drain: the mempool the rest of
+	// the VM observes must still report its backlog, just reordered, not
+	// emptied until the next Force.
+	if got := mempool.Len(context.Background()); got != 2 {
+		t.Fatalf("mempool has %d txs after Queue, want 2 reinserted", got)
+	}
+}
+
+func TestBuilderForceRepacksAndDelegates(t *testing.T) {
+	mempool := &fakeMempool{}
+	mempool.Add(context.Background(), []*chain.Transaction{
+		newTestTx(fakeChainAction{chainID: "a"}),
+	})
+	inner := &fakeInnerBuilder{}
+	b := NewBuilder(inner, mempool, NewScheduler(nil, nil), ^uint64(0))
+
+	if err := b.Force(context.Background()); err != nil {
+		t.Fatalf("Force: %v", err)
+	}
+	if inner.forced != 1 {
+		t.Fatalf("inner.Force called %d times, want 1", inner.forced)
+	}
+	if got := mempool.Len(context.Background()); got != 1 {
+		t.Fatalf("mempool has %d txs after Force, want 1 reinserted", got)
+	}
+}
+
+func TestBuilderQueueThenForceOrdersByWeight(t *testing.T) {
+	mempool := &fakeMempool{}
+	mempool.Add(context.Background(), []*chain.Transaction{
+		newTestTx(fakeChainAction{chainID: "a"}),
+		newTestTx(fakeChainAction{chainID: "b"}),
+		newTestTx(fakeChainAction{chainID: "a"}),
+	})
+	inner := &fakeInnerBuilder{}
+	b := NewBuilder(inner, mempool, NewScheduler(map[string]uint64{"a": 2, "b": 1}, nil), ^uint64(0))
+
+	b.Queue(context.Background())
+	if err := b.Force(context.Background()); err != nil {
+		t.Fatalf("Force: %v", err)
+	}
+
+	if got := mempool.Len(context.Background()); got != 3 {
+		t.Fatalf("mempool has %d txs after Queue+Force, want all 3 reinserted", got)
+	}
+	wantChains := []string{"a", "a", "b"}
+	for i, tx := range mempool.txs {
+		if got := ChainID(tx); got != wantChains[i] {
+			t.Fatalf("mempool.txs[%d] chainID = %q, want %q", i, got, wantChains[i])
+		}
+	}
+}
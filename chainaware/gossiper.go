@@ -0,0 +1,55 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chainaware
+
+import (
+	"context"
+
+	"github.com/AnomalyFi/hypersdk/gossiper"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// Gossiper wraps a gossiper.Gossiper (gossiper.NewProposer, typically) so
+// a gossip round draws from the same fairness-ordered batch the paired
+// Builder packs blocks from: TriggerGossip drains and re-packs the shared
+// Scheduler/Mempool the same way Builder.Force does, so one noisy
+// rollup's SequencerMsg backlog can't crowd the others out of gossip
+// bandwidth either.
+type Gossiper struct {
+	inner     gossiper.Gossiper
+	mempool   Mempool
+	scheduler *Scheduler
+
+	maxBatchBytes uint64
+}
+
+var _ gossiper.Gossiper = (*Gossiper)(nil)
+
+func NewGossiper(inner gossiper.Gossiper, mempool Mempool, scheduler *Scheduler, maxBatchBytes uint64) *Gossiper {
+	return &Gossiper{inner: inner, mempool: mempool, scheduler: scheduler, maxBatchBytes: maxBatchBytes}
+}
+
+func (g *Gossiper) Run() { g.inner.Run() }
+
+func (g *Gossiper) TriggerGossip(ctx context.Context) error {
+	for g.mempool.Len(ctx) > 0 {
+		tx, ok := g.mempool.PopMax(ctx)
+		if !ok {
+			break
+		}
+		g.scheduler.Enqueue(ChainID(tx), tx)
+	}
+	if ordered := g.scheduler.Pack(g.maxBatchBytes); len(ordered) > 0 {
+		g.mempool.Add(ctx, ordered)
+	}
+	return g.inner.TriggerGossip(ctx)
+}
+
+func (g *Gossiper) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg []byte) error {
+	return g.inner.HandleAppGossip(ctx, nodeID, msg)
+}
+
+// Scheduler exposes the underlying chain-aware scheduler so callers
+// (metrics, tests) can inspect queue depth directly.
+func (g *Gossiper) Scheduler() *Scheduler { return g.scheduler }
@@ -0,0 +1,145 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package chainaware gives actions.SequencerMsg fair-share treatment
+// across rollups: it partitions pending txs into per-chainID queues and
+// applies weighted round-robin (capped per chain) when a block is packed,
+// so one noisy rollup can't starve the others out of inclusion.
+package chainaware
+
+import (
+	"sync"
+	"time"
+
+	"github.com/AnomalyFi/hypersdk/chain"
+)
+
+// DefaultMaxBytesPerChain bounds how many bytes of a single chainID's txs
+// may land in one block when config.Config.ChainMaxBytesPerBlock doesn't
+// set an override for that chainID.
+const DefaultMaxBytesPerChain = 128 * 1024
+
+// GlobalChain is the queue key used for txs that don't carry a rollup
+// chainID (i.e. every action other than actions.SequencerMsg). It is
+// never subject to a per-chain byte cap.
+const GlobalChain = ""
+
+type queuedTx struct {
+	tx         *chain.Transaction
+	enqueuedAt time.Time
+}
+
+// Scheduler partitions incoming transactions by chainID and hands Pack
+// callers an ordered batch that respects each chain's weight and
+// max-bytes-per-block cap.
+type Scheduler struct {
+	mu               sync.Mutex
+	weights          map[string]uint64
+	maxBytesPerChain map[string]uint64
+	queues           map[string][]queuedTx
+	order            []string // first-seen order, stable across Pack calls
+
+	// OnEnqueue and OnPack, if set, are called under lock with the
+	// resulting queue depth / time since the packed tx was enqueued into
+	// this Scheduler, for a chainID. Controller
+	// wires these to its per-chain prometheus gauges/histograms.
+	OnEnqueue func(chainID string, depth int)
+	OnPack    func(chainID string, latency time.Duration)
+}
+
+func NewScheduler(weights, maxBytesPerChain map[string]uint64) *Scheduler {
+	return &Scheduler{
+		weights:          weights,
+		maxBytesPerChain: maxBytesPerChain,
+		queues:           make(map[string][]queuedTx),
+	}
+}
+
+func (s *Scheduler) weightFor(chainID string) uint64 {
+	if w, ok := s.weights[chainID]; ok && w > 0 {
+		return w
+	}
+	return 1
+}
+
+func (s *Scheduler) capFor(chainID string) uint64 {
+	if chainID == GlobalChain {
+		return ^uint64(0)
+	}
+	if c, ok := s.maxBytesPerChain[chainID]; ok && c > 0 {
+		return c
+	}
+	return DefaultMaxBytesPerChain
+}
+
+// Enqueue adds tx to chainID's queue (GlobalChain for non-SequencerMsg
+// txs). It is safe to call concurrently with Pack.
+func (s *Scheduler) Enqueue(chainID string, tx *chain.Transaction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.queues[chainID]; !ok {
+		s.order = append(s.order, chainID)
+	}
+	s.queues[chainID] = append(s.queues[chainID], queuedTx{tx: tx, enqueuedAt: time.Now()})
+	if s.OnEnqueue != nil {
+		s.OnEnqueue(chainID, len(s.queues[chainID]))
+	}
+}
+
+// QueueDepth reports how many txs are currently queued for chainID.
+func (s *Scheduler) QueueDepth(chainID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queues[chainID])
+}
+
+// Pack drains queued txs in weighted round-robin order, bounded by
+// maxBlockBytes overall and by each chain's cap individually, and returns
+// them in the order a block builder should include them.
+func (s *Scheduler) Pack(maxBlockBytes uint64) []*chain.Transaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var packed []*chain.Transaction
+	var totalBytes uint64
+	chainBytes := make(map[string]uint64, len(s.order))
+
+	for totalBytes < maxBlockBytes {
+		progressed := false
+		for _, chainID := range s.order {
+			q := s.queues[chainID]
+			cap := s.capFor(chainID)
+			weight := s.weightFor(chainID)
+			for i := uint64(0); i < weight && len(q) > 0; i++ {
+				tx := q[0]
+				size := uint64(len(tx.tx.Bytes()))
+				if totalBytes+size > maxBlockBytes || chainBytes[chainID]+size > cap {
+					break
+				}
+				packed = append(packed, tx.tx)
+				totalBytes += size
+				chainBytes[chainID] += size
+				if s.OnPack != nil {
+					s.OnPack(chainID, time.Since(tx.enqueuedAt))
+				}
+				q = q[1:]
+				progressed = true
+			}
+			s.queues[chainID] = q
+		}
+		if !progressed {
+			break
+		}
+	}
+	return packed
+}
+
+// ChainID extracts the rollup chainID a tx should be scheduled under:
+// actions.SequencerMsg's ChainID, or GlobalChain for everything else.
+func ChainID(tx *chain.Transaction) string {
+	if msg, ok := tx.Action.(interface{ GetChainID() string }); ok {
+		return msg.GetChainID()
+	}
+	return GlobalChain
+}
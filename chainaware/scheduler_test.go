@@ -0,0 +1,98 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chainaware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AnomalyFi/hypersdk/chain"
+	"github.com/AnomalyFi/hypersdk/codec"
+	"github.com/AnomalyFi/hypersdk/state"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// fakeChainAction is a bare-bones chain.Action that also carries a rollup
+// chainID the way actions.SequencerMsg does, so ChainID/Scheduler can be
+// exercised without depending on a real action implementation. size backs
+// Size() only; Marshal writes nothing.
+type fakeChainAction struct {
+	chainID string
+	size    int
+}
+
+func (a fakeChainAction) GetTypeID() uint8                           { return 0 }
+func (a fakeChainAction) StateKeys(codec.Address, ids.ID) state.Keys { return nil }
+func (a fakeChainAction) MaxUnits(chain.Rules) chain.Dimensions      { return chain.Dimensions{} }
+func (a fakeChainAction) Execute(
+	context.Context, chain.Rules, state.Mutable, int64, codec.Address, ids.ID,
+) (bool, chain.Dimensions, []byte, error) {
+	return true, chain.Dimensions{}, nil, nil
+}
+func (a fakeChainAction) Marshal(*codec.Packer) {}
+func (a fakeChainAction) Size() int             { return a.size }
+func (a fakeChainAction) GetChainID() string    { return a.chainID }
+
+func newTestTx(action chain.Action) *chain.Transaction {
+	return &chain.Transaction{Action: action}
+}
+
+func TestChainID(t *testing.T) {
+	if got := ChainID(newTestTx(fakeChainAction{chainID: "rollup-a"})); got != "rollup-a" {
+		t.Fatalf("ChainID() = %q, want %q", got, "rollup-a")
+	}
+	if got := ChainID(newTestTx(nil)); got != GlobalChain {
+		t.Fatalf("ChainID() for a non-SequencerMsg action = %q, want GlobalChain", got)
+	}
+}
+
+func TestSchedulerPackWeightedRoundRobin(t *testing.T) {
+	s := NewScheduler(map[string]uint64{"a": 2, "b": 1}, nil)
+
+	// Enqueue 3 txs for "a" and 3 for "b"; "a"'s weight of 2 should earn it
+	// two slots in the queue for every one of "b"'s in the packed order.
+	for i := 0; i < 3; i++ {
+		s.Enqueue("a", newTestTx(fakeChainAction{chainID: "a"}))
+		s.Enqueue("b", newTestTx(fakeChainAction{chainID: "b"}))
+	}
+
+	packed := s.Pack(^uint64(0))
+	if len(packed) != 6 {
+		t.Fatalf("Pack() returned %d txs, want 6", len(packed))
+	}
+
+	wantChains := []string{"a", "a", "b", "a", "b", "b"}
+	for i, tx := range packed {
+		if got := ChainID(tx); got != wantChains[i] {
+			t.Fatalf("packed[%d] chainID = %q, want %q", i, got, wantChains[i])
+		}
+	}
+}
+
+func TestSchedulerPackZeroBudgetLeavesQueueIntact(t *testing.T) {
+	s := NewScheduler(nil, nil)
+	s.Enqueue("a", newTestTx(fakeChainAction{chainID: "a"}))
+	s.Enqueue("a", newTestTx(fakeChainAction{chainID: "a"}))
+
+	if packed := s.Pack(0); len(packed) != 0 {
+		t.Fatalf("Pack(0) returned %d txs, want 0", len(packed))
+	}
+	if depth := s.QueueDepth("a"); depth != 2 {
+		t.Fatalf("QueueDepth(%q) = %d after a zero-budget Pack, want 2 untouched", "a", depth)
+	}
+}
+
+func TestSchedulerEnqueueOnEnqueueHook(t *testing.T) {
+	s := NewScheduler(nil, nil)
+	var gotChain string
+	var gotDepth int
+	s.OnEnqueue = func(chainID string, depth int) {
+		gotChain, gotDepth = chainID, depth
+	}
+
+	s.Enqueue("a", newTestTx(fakeChainAction{chainID: "a"}))
+	if gotChain != "a" || gotDepth != 1 {
+		t.Fatalf("OnEnqueue called with (%q, %d), want (%q, 1)", gotChain, gotDepth, "a")
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	"github.com/AnomalyFi/nodekit-seq/events"
+)
+
+const (
+	defaultLogLevel                 = "info"
+	defaultGossipInterval           = 100 * time.Millisecond
+	defaultGossipMaxSize            = 2 * 1024 * 1024
+	defaultGossipProposerDiff       = 4
+	defaultGossipProposerDepth      = 1
+	defaultBuildProposerDiff        = 4
+	defaultVerifyTimeout            = 5 * time.Second
+	defaultPreferredBlocksPerSecond = 1
+	defaultMaxBlockBytes            = 2 * 1024 * 1024
+	defaultSubscriberLag            = events.DefaultSubscriberLag
+)
+
+// DatabaseConfig describes how a single sub-database (block, state, or
+// metadata) should be instantiated. It is resolved by the db package's
+// factory and allows operators to point each sub-database at a different
+// engine, path, or tuning profile without recompiling the node.
+type DatabaseConfig struct {
+	// Type selects the storage engine: "pebble" (default), "leveldb", or
+	// "memdb" (in-memory, for tests). "badgerdb" is a registered name, but
+	// its factory always returns an error: no BadgerDB engine is linked in.
+	Type string `json:"type,omitempty"`
+
+	// Path overrides the default "<chainDataDir>/<name>" directory. Only
+	// consulted when UseStandalone is true.
+	Path string `json:"path,omitempty"`
+
+	// ConfigFile points at an engine-specific config file (e.g. a Pebble
+	// or LevelDB options file) to load in place of ConfigContent.
+	ConfigFile string `json:"configFile,omitempty"`
+
+	// ConfigContent is raw, engine-specific config content (same shape as
+	// what ConfigFile would contain) inlined directly in node config.
+	ConfigContent string `json:"configContent,omitempty"`
+
+	// UseStandalone, when true, directs this sub-database to Path instead
+	// of the chain data directory (e.g. to place state on faster disk).
+	UseStandalone *bool `json:"useStandalone,omitempty"`
+}
+
+func (d DatabaseConfig) standalone() bool {
+	return d.UseStandalone != nil && *d.UseStandalone
+}
+
+type Config struct {
+	NodeID ids.NodeID `json:"-"`
+
+	LogLevel string `json:"logLevel,omitempty"`
+
+	TestMode bool `json:"testMode,omitempty"`
+
+	GossipInterval      time.Duration `json:"gossipInterval,omitempty"`
+	GossipMaxSize       int           `json:"gossipMaxSize,omitempty"`
+	GossipProposerDiff  int           `json:"gossipProposerDiff,omitempty"`
+	GossipProposerDepth int           `json:"gossipProposerDepth,omitempty"`
+	BuildProposerDiff   int           `json:"buildProposerDiff,omitempty"`
+	VerifyTimeout       time.Duration `json:"verifyTimeout,omitempty"`
+
+	PreferredBlocksPerSecond int64 `json:"preferredBlocksPerSecond,omitempty"`
+
+	// ChainWeights gives each rollup chainID (as carried by
+	// actions.SequencerMsg) a weighted-round-robin share of every block
+	// the chainaware builder packs, so one noisy rollup can't starve the
+	// others out. A chainID absent from the map gets weight 1.
+	ChainWeights map[string]uint64 `json:"chainWeights,omitempty"`
+
+	// MaxBlockBytes bounds how many bytes of reordered txs the chainaware
+	// builder/gossiper reinsert into the mempool in one pass (see
+	// chainaware.Builder.Force / chainaware.Gossiper.TriggerGossip).
+	MaxBlockBytes uint64 `json:"maxBlockBytes,omitempty"`
+
+	// ChainMaxBytesPerBlock caps how many bytes of a single chainID's
+	// SequencerMsg txs may land in one block, regardless of its weight. A
+	// chainID absent from the map falls back to
+	// chainaware.DefaultMaxBytesPerChain.
+	ChainMaxBytesPerBlock map[string]uint64 `json:"chainMaxBytesPerBlock,omitempty"`
+
+	// SubscriberLag bounds how many unread events (see events.Broadcaster)
+	// a subscriber to Controller.subscribers may queue up before being
+	// dropped for falling behind. Zero falls back to
+	// events.DefaultSubscriberLag.
+	SubscriberLag int `json:"subscriberLag,omitempty"`
+
+	// PluginDir, if set, is scanned at startup for executable plugin
+	// binaries that register additional chain.Action/chain.Auth
+	// implementations. See the plugin package for the contract they must
+	// speak.
+	PluginDir string `json:"pluginDir,omitempty"`
+
+	// BlockDB, StateDB, and MetadataDB configure each sub-database
+	// independently. Any field left unset falls back to the Pebble
+	// default in the chain data directory.
+	BlockDB    DatabaseConfig `json:"blockDB,omitempty"`
+	StateDB    DatabaseConfig `json:"stateDB,omitempty"`
+	MetadataDB DatabaseConfig `json:"metadataDB,omitempty"`
+}
+
+func New(nodeID ids.NodeID, b []byte) (*Config, error) {
+	c := &Config{
+		NodeID:                   nodeID,
+		LogLevel:                 defaultLogLevel,
+		GossipInterval:           defaultGossipInterval,
+		GossipMaxSize:            defaultGossipMaxSize,
+		GossipProposerDiff:       defaultGossipProposerDiff,
+		GossipProposerDepth:      defaultGossipProposerDepth,
+		BuildProposerDiff:        defaultBuildProposerDiff,
+		VerifyTimeout:            defaultVerifyTimeout,
+		PreferredBlocksPerSecond: defaultPreferredBlocksPerSecond,
+		MaxBlockBytes:            defaultMaxBlockBytes,
+		SubscriberLag:            defaultSubscriberLag,
+	}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *Config) GetLogLevel() logging.Level {
+	lvl, err := logging.ToLevel(c.LogLevel)
+	if err != nil {
+		return logging.Info
+	}
+	return lvl
+}
+
+func (c *Config) GetPreferredBlocksPerSecond() int64 {
+	return c.PreferredBlocksPerSecond
+}
+
+func (c *Config) GetMaxBlockBytes() uint64 {
+	if c.MaxBlockBytes == 0 {
+		return defaultMaxBlockBytes
+	}
+	return c.MaxBlockBytes
+}
+
+// GetSubscriberLag returns SubscriberLag, or events.DefaultSubscriberLag if
+// unset.
+func (c *Config) GetSubscriberLag() int {
+	if c.SubscriberLag <= 0 {
+		return events.DefaultSubscriberLag
+	}
+	return c.SubscriberLag
+}
+
+// GetDatabaseConfig returns the configuration for the named sub-database
+// ("block", "state", or "metadata"). Unknown names return the zero value,
+// which resolves to the Pebble default.
+func (c *Config) GetDatabaseConfig(name string) DatabaseConfig {
+	switch name {
+	case "block":
+		return c.BlockDB
+	case "state":
+		return c.StateDB
+	case "metadata":
+		return c.MetadataDB
+	default:
+		return DatabaseConfig{}
+	}
+}
+
+// Standalone reports whether this sub-database should use its own Path
+// rather than living under the chain data directory.
+func (d DatabaseConfig) Standalone() bool {
+	return d.standalone()
+}
@@ -0,0 +1,19 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package consts
+
+// ActionName returns the registered name for a given action type ID, and
+// false if no action was registered at that ID. It reads
+// ActionRegistry.GetRegisteredTypes() live rather than caching it in a
+// package var: built-in actions register themselves from their own
+// package's init(), and plugin actions register even later, during
+// Controller.Initialize, so any value cached at var-initialization time
+// would be stale for the lifetime of the process.
+func ActionName(id uint8) (string, bool) {
+	actionNames := ActionRegistry.GetRegisteredTypes()
+	if int(id) >= len(actionNames) {
+		return "", false
+	}
+	return actionNames[id], true
+}
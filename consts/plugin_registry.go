@@ -0,0 +1,19 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package consts
+
+import (
+	"github.com/AnomalyFi/hypersdk/chain"
+	"github.com/AnomalyFi/hypersdk/codec"
+)
+
+// RegisterPluginAction merges an action discovered by the plugin loader
+// into ActionRegistry under its reserved TypeID. unmarshal is plugin.Adapt's
+// second return value: it decodes each tx's own marshaled bytes into a
+// fresh adapter instance, the same way a built-in action's Unmarshal would,
+// instead of every tx of this type resolving to the single instance it was
+// registered with.
+func RegisterPluginAction(name string, a chain.Action, unmarshal func(*codec.Packer) (chain.Action, error)) error {
+	return ActionRegistry.Register(a, unmarshal, false)
+}
@@ -10,22 +10,26 @@ import (
 	"github.com/AnomalyFi/hypersdk/builder"
 	"github.com/AnomalyFi/hypersdk/chain"
 	"github.com/AnomalyFi/hypersdk/gossiper"
-	"github.com/AnomalyFi/hypersdk/pebble"
 	hrpc "github.com/AnomalyFi/hypersdk/rpc"
 	"github.com/AnomalyFi/hypersdk/utils"
 	"github.com/AnomalyFi/hypersdk/vm"
 	ametrics "github.com/ava-labs/avalanchego/api/metrics"
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"go.uber.org/zap"
 
 	"github.com/AnomalyFi/nodekit-seq/actions"
 	"github.com/AnomalyFi/nodekit-seq/auth"
+	"github.com/AnomalyFi/nodekit-seq/chainaware"
 	"github.com/AnomalyFi/nodekit-seq/config"
 	"github.com/AnomalyFi/nodekit-seq/consts"
+	"github.com/AnomalyFi/nodekit-seq/db"
+	"github.com/AnomalyFi/nodekit-seq/events"
 	"github.com/AnomalyFi/nodekit-seq/genesis"
 	"github.com/AnomalyFi/nodekit-seq/orderbook"
+	"github.com/AnomalyFi/nodekit-seq/plugin"
 	"github.com/AnomalyFi/nodekit-seq/rpc"
 	"github.com/AnomalyFi/nodekit-seq/storage"
 	"github.com/AnomalyFi/nodekit-seq/version"
@@ -41,9 +45,12 @@ type Controller struct {
 	config       *config.Config
 	stateManager *StateManager
 
-	metrics *metrics
+	metrics     *metrics
+	subscribers *events.Broadcaster
+	scheduler   *chainaware.Scheduler
 
-	metaDB database.Database
+	metaDB  database.Database
+	plugins []*plugin.Loaded
 }
 
 func New() *vm.VM {
@@ -87,6 +94,7 @@ func (c *Controller) Initialize(
 	}
 	c.snowCtx.Log.SetLevel(c.config.GetLogLevel())
 	snowCtx.Log.Info("loaded config", zap.Any("contents", c.config))
+	c.subscribers = events.NewBroadcaster(c.config.GetSubscriberLag())
 
 	c.genesis, err = genesis.New(genesisBytes, upgradeBytes)
 	if err != nil {
@@ -98,13 +106,15 @@ func (c *Controller) Initialize(
 	snowCtx.Log.Info("loaded genesis", zap.Any("genesis", c.genesis))
 
 	// Create DBs
+	//
+	// Each sub-database can be pointed at its own engine, path, and tuning
+	// profile via [config.Config.GetDatabaseConfig]; anything left unset
+	// falls back to the Pebble default in the chain data directory.
 	blockPath, err := utils.InitSubDirectory(snowCtx.ChainDataDir, "block")
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
-	// TODO: tune Pebble config based on each sub-db focus
-	cfg := pebble.NewDefaultConfig()
-	blockDB, err := pebble.New(blockPath, cfg)
+	blockDB, err := db.NewFromConfig(blockPath, c.config.GetDatabaseConfig("block"))
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
@@ -112,7 +122,7 @@ func (c *Controller) Initialize(
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
-	stateDB, err := pebble.New(statePath, cfg)
+	stateDB, err := db.NewFromConfig(statePath, c.config.GetDatabaseConfig("state"))
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
@@ -120,7 +130,7 @@ func (c *Controller) Initialize(
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
-	c.metaDB, err = pebble.New(metaPath, cfg)
+	c.metaDB, err = db.NewFromConfig(metaPath, c.config.GetDatabaseConfig("metadata"))
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
@@ -139,6 +149,7 @@ func (c *Controller) Initialize(
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
 	apis[rpc.JSONRPCEndpoint] = jsonRPCHandler
+	apis[rpc.StreamEndpoint] = rpc.NewStreamHandler(c.subscribers)
 
 	// Create builder and gossiper
 	var (
@@ -163,6 +174,35 @@ func (c *Controller) Initialize(
 		gossip = gossiper.NewProposer(inner, gcfg)
 	}
 
+	// Wrap the builder/gossiper so actions.SequencerMsg txs get fair-share
+	// ordering by rollup chainID instead of being treated uniformly: both
+	// drain inner.Mempool() into the scheduler and reinsert the
+	// weighted-round-robin result before delegating to the wrapped
+	// builder/gossiper.
+	c.scheduler = chainaware.NewScheduler(c.config.ChainWeights, c.config.ChainMaxBytesPerBlock)
+	c.scheduler.OnEnqueue = c.metrics.recordChainQueueDepth
+	c.scheduler.OnPack = c.metrics.recordChainInclusionLatency
+	maxBlockBytes := c.config.GetMaxBlockBytes()
+	build = chainaware.NewBuilder(build, inner.Mempool(), c.scheduler, maxBlockBytes)
+	gossip = chainaware.NewGossiper(gossip, inner.Mempool(), c.scheduler, maxBlockBytes)
+
+	// Discover and register any action plugins before handing back the
+	// registries: once Initialize returns, hypersdk treats them as fixed
+	// for the lifetime of the VM.
+	c.plugins, err = plugin.LoadDir(c.snowCtx.Log, c.config.PluginDir)
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, nil, nil, nil, err
+	}
+	for _, loaded := range c.plugins {
+		action, unmarshal := plugin.Adapt(loaded)
+		if err := consts.RegisterPluginAction(loaded.Name, action, unmarshal); err != nil {
+			return nil, nil, nil, nil, nil, nil, nil, nil, nil, fmt.Errorf(
+				"registering plugin %q: %w",
+				loaded.Name,
+				err,
+			)
+		}
+	}
 
 	return c.config, c.genesis, build, gossip, blockDB, stateDB, apis, consts.ActionRegistry, consts.AuthRegistry, nil
 }
@@ -181,6 +221,8 @@ func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) er
 	defer batch.Reset()
 
 	results := blk.Results()
+	txIDs := make([]ids.ID, len(blk.Txs))
+	actionEvents := make([]events.ActionAccepted, len(blk.Txs))
 	for i, tx := range blk.Txs {
 		result := results[i]
 		err := storage.StoreTransaction(
@@ -194,36 +236,50 @@ func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) er
 		if err != nil {
 			return err
 		}
-		if result.Success {
-			switch action := tx.Action.(type) {
-			case *actions.CreateAsset:
-				c.metrics.createAsset.Inc()
-			case *actions.MintAsset:
-				c.metrics.mintAsset.Inc()
-			case *actions.BurnAsset:
-				c.metrics.burnAsset.Inc()
-			case *actions.ModifyAsset:
-				c.metrics.modifyAsset.Inc()
-			case *actions.Transfer:
-				c.metrics.transfer.Inc()
-			case *actions.SequencerMsg
-				c.metrics.sequencerMsg.Inc()
-			case *actions.ImportAsset:
-				c.metrics.importAsset.Inc()
-			case *actions.ExportAsset:
-				c.metrics.exportAsset.Inc()
-			}
+		action, ok := consts.ActionName(tx.Action.GetTypeID())
+		if !ok {
+			action = "unknown"
 		}
+		c.metrics.recordTx(action, result.Success, result.Units)
+
+		txIDs[i] = tx.ID()
+		chainID := ""
+		if msg, ok := tx.Action.(*actions.SequencerMsg); ok {
+			chainID = msg.ChainID
+		}
+		actionEvents[i] = events.ActionAccepted{
+			Height:  blk.Height(),
+			TxID:    tx.ID(),
+			Action:  action,
+			Signer:  tx.Auth.Actor().String(),
+			ChainID: chainID,
+			Units:   result.Units,
+			Success: result.Success,
+		}
+	}
+	c.metrics.recordBlock(len(blk.Txs))
+
+	if err := batch.Write(); err != nil {
+		return err
 	}
-	return batch.Write()
+
+	c.subscribers.Publish(events.BlockAccepted{
+		Height:    blk.Height(),
+		Timestamp: blk.GetTimestamp(),
+		TxIDs:     txIDs,
+	}, actionEvents)
+	return nil
 }
 
 func (*Controller) Rejected(context.Context, *chain.StatelessBlock) error {
 	return nil
 }
 
-func (*Controller) Shutdown(context.Context) error {
+func (c *Controller) Shutdown(context.Context) error {
 	// Do not close any databases provided during initialization. The VM will
 	// close any databases your provided.
+	for _, loaded := range c.plugins {
+		loaded.Close()
+	}
 	return nil
 }
@@ -0,0 +1,112 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package controller
+
+import (
+	"time"
+
+	ametrics "github.com/ava-labs/avalanchego/api/metrics"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/AnomalyFi/nodekit-seq/consts"
+)
+
+// metrics is registered per action ID via consts.ActionRegistry instead of
+// one hardcoded field per action, so a new action (e.g. SequencerMsg) gets
+// counters for free without touching this file or Controller.Accepted.
+type metrics struct {
+	txsTotal   *prometheus.CounterVec // labels: action, status
+	unitsTotal *prometheus.CounterVec // labels: action
+	blockTxs   prometheus.Histogram
+
+	// chainQueueDepth and chainInclusionLatency are fed by the chainaware
+	// scheduler's OnEnqueue/OnPack hooks; labeled by rollup chainID (the
+	// empty-string label is chainaware.GlobalChain, everything else).
+	chainQueueDepth       *prometheus.GaugeVec     // labels: chain
+	chainInclusionLatency *prometheus.HistogramVec // labels: chain
+}
+
+func newMetrics(gatherer ametrics.MultiGatherer) (*metrics, error) {
+	r := prometheus.NewRegistry()
+	m := &metrics{
+		txsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "chain",
+			Name:      "txs_total",
+			Help:      "number of transactions processed, by action and outcome",
+		}, []string{"action", "status"}),
+		unitsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "chain",
+			Name:      "units_total",
+			Help:      "units consumed by successful transactions, by action",
+		}, []string{"action"}),
+		blockTxs: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "chain",
+			Name:      "block_txs",
+			Help:      "number of transactions included per accepted block",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+		}),
+		chainQueueDepth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "chain",
+			Name:      "sequencer_queue_depth",
+			Help:      "pending txs queued in the chain-aware scheduler per rollup chainID (empty chain label: txs without one)",
+		}, []string{"chain"}),
+		chainInclusionLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "chain",
+			Name:      "sequencer_inclusion_latency_seconds",
+			Help:      "time between a tx being enqueued into the chain-aware scheduler and packed by it, per rollup chainID; each repack re-enqueues drained txs, so time spent in the mempool before that is not counted",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"chain"}),
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		r.Register(m.txsTotal),
+		r.Register(m.unitsTotal),
+		r.Register(m.blockTxs),
+		r.Register(m.chainQueueDepth),
+		r.Register(m.chainInclusionLatency),
+	)
+	if errs.Errored() {
+		return nil, errs.Err
+	}
+
+	// Pre-register a zero-valued series for every action known to the
+	// registry at startup so dashboards don't show gaps before the first
+	// tx of a given type lands.
+	for _, action := range consts.ActionRegistry.GetRegisteredTypes() {
+		m.txsTotal.WithLabelValues(action, "success")
+		m.txsTotal.WithLabelValues(action, "fail")
+		m.unitsTotal.WithLabelValues(action)
+	}
+
+	return m, gatherer.Register(consts.Name, r)
+}
+
+// recordTx bumps the counters for a single executed transaction. action is
+// looked up by the caller from tx.Action.GetTypeID() via consts.ActionRegistry.
+func (m *metrics) recordTx(action string, success bool, units uint64) {
+	status := "fail"
+	if success {
+		status = "success"
+	}
+	m.txsTotal.WithLabelValues(action, status).Inc()
+	if success {
+		m.unitsTotal.WithLabelValues(action).Add(float64(units))
+	}
+}
+
+func (m *metrics) recordBlock(numTxs int) {
+	m.blockTxs.Observe(float64(numTxs))
+}
+
+func (m *metrics) recordChainQueueDepth(chainID string, depth int) {
+	m.chainQueueDepth.WithLabelValues(chainID).Set(float64(depth))
+}
+
+func (m *metrics) recordChainInclusionLatency(chainID string, latency time.Duration) {
+	m.chainInclusionLatency.WithLabelValues(chainID).Observe(latency.Seconds())
+	// Each OnPack call is one tx leaving chainID's queue.
+	m.chainQueueDepth.WithLabelValues(chainID).Dec()
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package db provides a small factory so that each of the controller's
+// sub-databases (block, state, metadata) can be backed by a different
+// storage engine, chosen at runtime via config.DatabaseConfig instead of
+// being hard-wired to Pebble.
+package db
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/AnomalyFi/hypersdk/pebble"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/leveldb"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	"github.com/AnomalyFi/nodekit-seq/config"
+)
+
+// Factory constructs a database.Database rooted at path using the given
+// sub-database config. path is the default location (a subdirectory of
+// the chain data dir); a factory may ignore it if cfg.Standalone() and
+// cfg.Path are set.
+type Factory func(path string, cfg config.DatabaseConfig) (database.Database, error)
+
+var registry = map[string]Factory{}
+
+// Register adds a named engine to the factory. Third parties can call this
+// from an init() in their own package to plug in additional engines.
+func Register(name string, f Factory) {
+	registry[name] = f
+}
+
+func init() {
+	Register("pebble", newPebble)
+	Register("leveldb", newLevelDB)
+	Register("memdb", newMemDB)
+	Register("badgerdb", newBadgerDB)
+}
+
+// NewFromConfig resolves cfg.Type (defaulting to "pebble") and constructs
+// the corresponding database rooted at defaultPath, unless cfg selects a
+// standalone path of its own.
+func NewFromConfig(defaultPath string, cfg config.DatabaseConfig) (database.Database, error) {
+	typ := cfg.Type
+	if typ == "" {
+		typ = "pebble"
+	}
+	factory, ok := registry[typ]
+	if !ok {
+		return nil, fmt.Errorf("unknown database type %q", typ)
+	}
+	path := defaultPath
+	if cfg.Standalone() && cfg.Path != "" {
+		path = cfg.Path
+	}
+	return factory(path, cfg)
+}
+
+// resolveConfigContent returns the engine-specific config content to
+// deserialize: cfg.ConfigFile's contents if set, otherwise cfg.ConfigContent
+// verbatim. An empty result means "use engine defaults".
+func resolveConfigContent(cfg config.DatabaseConfig) ([]byte, error) {
+	if cfg.ConfigFile != "" {
+		b, err := os.ReadFile(cfg.ConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading config file %q: %w", cfg.ConfigFile, err)
+		}
+		return b, nil
+	}
+	if cfg.ConfigContent != "" {
+		return []byte(cfg.ConfigContent), nil
+	}
+	return nil, nil
+}
+
+func newPebble(path string, cfg config.DatabaseConfig) (database.Database, error) {
+	pcfg := pebble.NewDefaultConfig()
+	content, err := resolveConfigContent(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > 0 {
+		if err := pcfg.Deserialize(content); err != nil {
+			return nil, fmt.Errorf("invalid pebble config: %w", err)
+		}
+	}
+	return pebble.New(path, pcfg)
+}
+
+func newLevelDB(path string, cfg config.DatabaseConfig) (database.Database, error) {
+	lcfg := leveldb.DefaultConfig()
+	content, err := resolveConfigContent(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > 0 {
+		if err := lcfg.Deserialize(content); err != nil {
+			return nil, fmt.Errorf("invalid leveldb config: %w", err)
+		}
+	}
+	return leveldb.New(path, lcfg, logging.NoLog{}, "db", nil)
+}
+
+func newMemDB(string, config.DatabaseConfig) (database.Database, error) {
+	return memdb.New(), nil
+}
+
+// newBadgerDB is a placeholder factory. BadgerDB support is planned but not
+// yet wired up; third parties can override it via Register until then.
+func newBadgerDB(string, config.DatabaseConfig) (database.Database, error) {
+	return nil, fmt.Errorf("badgerdb backend is not yet implemented")
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AnomalyFi/nodekit-seq/config"
+)
+
+func TestNewFromConfigMemDB(t *testing.T) {
+	d, err := NewFromConfig(t.TempDir(), config.DatabaseConfig{Type: "memdb"})
+	if err != nil {
+		t.Fatalf("NewFromConfig: %v", err)
+	}
+	defer d.Close()
+
+	if err := d.Put([]byte("k"), []byte("v")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	v, err := d.Get([]byte("k"))
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(v) != "v" {
+		t.Fatalf("Get returned %q, want %q", v, "v")
+	}
+}
+
+func TestNewFromConfigDefaultsToPebble(t *testing.T) {
+	// NewFromConfig maps an unset Type to "pebble". This checks the registry
+	// side of that without opening a database: there is no entry under ""
+	// and there is one under "pebble".
+	if _, ok := registry[""]; ok {
+		t.Fatal(`registry unexpectedly has an entry for ""`)
+	}
+	if _, ok := registry["pebble"]; !ok {
+		t.Fatal(`registry has no "pebble" entry; NewFromConfig's default would fail`)
+	}
+}
+
+func TestNewFromConfigUnknownType(t *testing.T) {
+	_, err := NewFromConfig(t.TempDir(), config.DatabaseConfig{Type: "does-not-exist"})
+	if err == nil {
+		t.Fatal("NewFromConfig with an unregistered type returned no error")
+	}
+}
+
+func TestNewFromConfigBadgerDBNotImplemented(t *testing.T) {
+	_, err := NewFromConfig(t.TempDir(), config.DatabaseConfig{Type: "badgerdb"})
+	if err == nil {
+		t.Fatal("NewFromConfig(\"badgerdb\") returned no error, want the not-implemented stub error")
+	}
+}
+
+func TestResolveConfigContentPrefersConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.cfg")
+	if err := os.WriteFile(path, []byte("from-file"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	content, err := resolveConfigContent(config.DatabaseConfig{
+		ConfigFile:    path,
+		ConfigContent: "from-inline-content",
+	})
+	if err != nil {
+		t.Fatalf("resolveConfigContent: %v", err)
+	}
+	if string(content) != "from-file" {
+		t.Fatalf("resolveConfigContent = %q, want %q (ConfigFile should take precedence)", content, "from-file")
+	}
+}
+
+func TestResolveConfigContentFallsBackToInline(t *testing.T) {
+	content, err := resolveConfigContent(config.DatabaseConfig{ConfigContent: "from-inline-content"})
+	if err != nil {
+		t.Fatalf("resolveConfigContent: %v", err)
+	}
+	if string(content) != "from-inline-content" {
+		t.Fatalf("resolveConfigContent = %q, want %q", content, "from-inline-content")
+	}
+}
+
+func TestResolveConfigContentMissingFile(t *testing.T) {
+	_, err := resolveConfigContent(config.DatabaseConfig{ConfigFile: filepath.Join(t.TempDir(), "missing.cfg")})
+	if err == nil {
+		t.Fatal("resolveConfigContent with a nonexistent ConfigFile returned no error")
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package events
+
+import "sync"
+
+// DefaultRingSize bounds how many recent blocks Replay can serve. Older
+// blocks fall off the ring; a client asking to replay past that point gets
+// ErrTooFarBehind and must resync from GetBlock/GetTransaction instead.
+const DefaultRingSize = 8192
+
+// DefaultSubscriberLag is how many unread events a subscriber may queue up
+// before Broadcaster drops it rather than blocking block acceptance on a
+// slow client.
+const DefaultSubscriberLag = 1024
+
+type record struct {
+	block   BlockAccepted
+	actions []ActionAccepted
+}
+
+// Broadcaster fans out accepted blocks/actions to subscribers and keeps a
+// bounded ring of recent blocks so a freshly (re)connected client can
+// replay from a given height instead of missing events.
+type Broadcaster struct {
+	mu   sync.Mutex
+	subs map[uint64]*Subscription
+	next uint64
+
+	// ring is keyed by height % DefaultRingSize from the very first insert,
+	// so a slot's occupant is always the most recent block at that height
+	// mod DefaultRingSize, not whatever landed there in append order.
+	// ringSet tracks which slots have ever been written, since ring starts
+	// out as a zero-valued record (height 0) in every slot.
+	ring      [DefaultRingSize]record
+	ringSet   [DefaultRingSize]bool
+	haveRing  bool
+	maxHeight uint64
+
+	// lag is the per-subscriber channel capacity passed to Subscribe. A
+	// non-positive lag falls back to DefaultSubscriberLag rather than
+	// producing an unbuffered (or negatively sized) channel.
+	lag int
+}
+
+// NewBroadcaster returns a Broadcaster whose subscribers may queue up to
+// lag unread events before being dropped. lag <= 0 falls back to
+// DefaultSubscriberLag.
+func NewBroadcaster(lag int) *Broadcaster {
+	if lag <= 0 {
+		lag = DefaultSubscriberLag
+	}
+	return &Broadcaster{subs: make(map[uint64]*Subscription), lag: lag}
+}
+
+// Subscription is a single consumer's inbox. Events is the channel to
+// range over; it is closed once the subscriber is dropped (by Unsubscribe
+// or for falling too far behind).
+type Subscription struct {
+	id     uint64
+	filter Filter
+
+	Events  chan any // BlockAccepted or ActionAccepted
+	dropped bool
+}
+
+// ID identifies this subscription for a later Unsubscribe call.
+func (s *Subscription) ID() uint64 { return s.id }
+
+// Dropped reports whether the broadcaster closed this subscription because
+// the consumer fell too far behind.
+func (s *Subscription) Dropped() bool { return s.dropped }
+
+func (b *Broadcaster) Subscribe(filter Filter) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.next++
+	sub := &Subscription{
+		id:     b.next,
+		filter: filter,
+		Events: make(chan any, b.lag),
+	}
+	b.subs[sub.id] = sub
+	return sub
+}
+
+func (b *Broadcaster) Unsubscribe(id uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if sub, ok := b.subs[id]; ok {
+		close(sub.Events)
+		delete(b.subs, id)
+	}
+}
+
+// Publish fans out one accepted block and its per-tx action events to
+// every matching subscriber, dropping (and unsubscribing) any subscriber
+// whose inbox is full rather than blocking the caller.
+func (b *Broadcaster) Publish(block BlockAccepted, actions []ActionAccepted) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.appendRing(block, actions)
+
+	for id, sub := range b.subs {
+		b.deliver(sub, block)
+		for _, a := range actions {
+			if !sub.filter.matches(&a) {
+				continue
+			}
+			b.deliver(sub, a)
+		}
+		if sub.dropped {
+			delete(b.subs, id)
+		}
+	}
+}
+
+// deliver is non-blocking: a full channel marks the subscription dropped
+// instead of stalling block acceptance on one slow consumer.
+func (b *Broadcaster) deliver(sub *Subscription, event any) {
+	if sub.dropped {
+		return
+	}
+	select {
+	case sub.Events <- event:
+	default:
+		sub.dropped = true
+		close(sub.Events)
+	}
+}
+
+func (b *Broadcaster) appendRing(block BlockAccepted, actions []ActionAccepted) {
+	idx := int(block.Height % DefaultRingSize)
+	b.ring[idx] = record{block, actions}
+	b.ringSet[idx] = true
+	b.haveRing = true
+	if block.Height > b.maxHeight {
+		b.maxHeight = block.Height
+	}
+}
+
+// oldestAvailable returns the lowest height still guaranteed to be in the
+// ring, assuming heights have been appended contiguously.
+func (b *Broadcaster) oldestAvailable() uint64 {
+	if b.maxHeight+1 <= DefaultRingSize {
+		return 0
+	}
+	return b.maxHeight - DefaultRingSize + 1
+}
+
+// ErrTooFarBehind is returned by Replay when fromHeight has already fallen
+// out of the ring buffer.
+type ErrTooFarBehind struct {
+	Requested, OldestAvailable uint64
+}
+
+func (e *ErrTooFarBehind) Error() string {
+	return "requested height is older than the retained event ring"
+}
+
+// Replay returns every retained block/action pair at or after fromHeight,
+// oldest first, for a client resuming a subscription after a disconnect.
+func (b *Broadcaster) Replay(fromHeight uint64) ([]BlockAccepted, [][]ActionAccepted, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.haveRing {
+		return nil, nil, nil
+	}
+
+	oldest := b.oldestAvailable()
+	if fromHeight < oldest {
+		return nil, nil, &ErrTooFarBehind{Requested: fromHeight, OldestAvailable: oldest}
+	}
+	if oldest < fromHeight {
+		oldest = fromHeight
+	}
+
+	var blocks []BlockAccepted
+	var actions [][]ActionAccepted
+	for h := oldest; h <= b.maxHeight; h++ {
+		idx := int(h % DefaultRingSize)
+		// A slot whose occupant's height doesn't match h was either never
+		// written (gap in acceptance) or has since been overwritten by a
+		// later height DefaultRingSize apart; either way h isn't available.
+		if !b.ringSet[idx] || b.ring[idx].block.Height != h {
+			continue
+		}
+		blocks = append(blocks, b.ring[idx].block)
+		actions = append(actions, b.ring[idx].actions)
+	}
+	return blocks, actions, nil
+}
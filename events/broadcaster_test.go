@@ -0,0 +1,120 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package events
+
+import "testing"
+
+func TestBroadcasterReplayBeforeWrap(t *testing.T) {
+	b := NewBroadcaster(0)
+	for h := uint64(0); h < 5; h++ {
+		b.Publish(BlockAccepted{Height: h}, nil)
+	}
+
+	blocks, _, err := b.Replay(2)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if len(blocks) != 3 {
+		t.Fatalf("Replay(2) returned %d blocks, want 3", len(blocks))
+	}
+	for i, blk := range blocks {
+		if want := uint64(2 + i); blk.Height != want {
+			t.Fatalf("blocks[%d].Height = %d, want %d", i, blk.Height, want)
+		}
+	}
+}
+
+func TestBroadcasterReplayAcrossWrap(t *testing.T) {
+	b := NewBroadcaster(0)
+	// Publish well past DefaultRingSize so the ring wraps several times
+	// over; only the most recent DefaultRingSize heights should survive.
+	top := uint64(DefaultRingSize) * 2
+	for h := uint64(0); h <= top; h++ {
+		b.Publish(BlockAccepted{Height: h}, nil)
+	}
+
+	oldest := top - uint64(DefaultRingSize) + 1
+	blocks, _, err := b.Replay(oldest)
+	if err != nil {
+		t.Fatalf("Replay(%d): %v", oldest, err)
+	}
+	if len(blocks) != DefaultRingSize {
+		t.Fatalf("Replay(%d) returned %d blocks, want %d", oldest, len(blocks), DefaultRingSize)
+	}
+	for i, blk := range blocks {
+		if want := oldest + uint64(i); blk.Height != want {
+			t.Fatalf("blocks[%d].Height = %d, want %d (ring wrap produced out-of-order/duplicate heights)", i, blk.Height, want)
+		}
+	}
+}
+
+func TestBroadcasterReplayTooFarBehind(t *testing.T) {
+	b := NewBroadcaster(0)
+	top := uint64(DefaultRingSize) * 2
+	for h := uint64(0); h <= top; h++ {
+		b.Publish(BlockAccepted{Height: h}, nil)
+	}
+
+	_, _, err := b.Replay(0)
+	if err == nil {
+		t.Fatal("Replay(0) after the ring wrapped returned no error, want ErrTooFarBehind")
+	}
+	tooFar, ok := err.(*ErrTooFarBehind)
+	if !ok {
+		t.Fatalf("Replay(0) error is %T, want *ErrTooFarBehind", err)
+	}
+	if want := top - uint64(DefaultRingSize) + 1; tooFar.OldestAvailable != want {
+		t.Fatalf("OldestAvailable = %d, want %d", tooFar.OldestAvailable, want)
+	}
+}
+
+func TestBroadcasterPublishDeliversToMatchingSubscribers(t *testing.T) {
+	b := NewBroadcaster(0)
+	sub := b.Subscribe(Filter{ChainID: "rollup-a"})
+
+	b.Publish(BlockAccepted{Height: 1}, []ActionAccepted{
+		{Height: 1, ChainID: "rollup-a"},
+		{Height: 1, ChainID: "rollup-b"},
+	})
+
+	// BlockAccepted always matches, then exactly the one ActionAccepted
+	// whose ChainID matches the subscription's filter.
+	if _, ok := (<-sub.Events).(BlockAccepted); !ok {
+		t.Fatal("first event was not a BlockAccepted")
+	}
+	action, ok := (<-sub.Events).(ActionAccepted)
+	if !ok {
+		t.Fatal("second event was not an ActionAccepted")
+	}
+	if action.ChainID != "rollup-a" {
+		t.Fatalf("delivered action ChainID = %q, want %q (filter should have excluded rollup-b)", action.ChainID, "rollup-a")
+	}
+	select {
+	case e := <-sub.Events:
+		t.Fatalf("unexpected extra event delivered: %#v", e)
+	default:
+	}
+}
+
+func TestBroadcasterSubscriberLagDropsSlowConsumer(t *testing.T) {
+	b := NewBroadcaster(2)
+	sub := b.Subscribe(Filter{})
+
+	// The subscriber never reads, so the third publish should find its
+	// inbox full (capacity 2) and drop it rather than block.
+	for h := uint64(0); h < 3; h++ {
+		b.Publish(BlockAccepted{Height: h}, nil)
+	}
+
+	if !sub.Dropped() {
+		t.Fatal("subscriber should have been dropped after exceeding the configured lag")
+	}
+	drained := 0
+	for range sub.Events {
+		drained++
+	}
+	if drained != 2 {
+		t.Fatalf("drained %d buffered events before close, want 2 (the configured lag)", drained)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package events fans out every accepted block/tx/action out to external
+// consumers (rollup indexers, in particular) so they don't need to poll
+// GetTransaction. Controller.Accepted publishes into a Broadcaster; the
+// rpc package exposes it over WebSocket only (rpc.StreamEndpoint), and
+// JSONRPCServer has no subscribe/unsubscribe/replay methods.
+package events
+
+import "github.com/ava-labs/avalanchego/ids"
+
+// BlockAccepted is published once per accepted block.
+type BlockAccepted struct {
+	Height    uint64   `json:"height"`
+	Timestamp int64    `json:"timestamp"`
+	TxIDs     []ids.ID `json:"txIDs"`
+}
+
+// ActionAccepted is published once per tx in an accepted block.
+type ActionAccepted struct {
+	Height uint64 `json:"height"`
+	TxID   ids.ID `json:"txID"`
+	// Action is the registered name from consts.ActionRegistry (see
+	// consts.ActionName), not the Go type, so plugin-discovered actions
+	// show up the same way built-in ones do.
+	Action string `json:"action"`
+	Signer string `json:"signer"`
+	// ChainID is set only for actions that carry a rollup chain ID (e.g.
+	// actions.SequencerMsg); it is the empty string otherwise.
+	ChainID string `json:"chainID,omitempty"`
+	Units   uint64 `json:"units"`
+	Success bool   `json:"success"`
+}
+
+// Filter narrows a subscription down to the events a consumer cares
+// about. A zero-valued field means "don't filter on this dimension".
+type Filter struct {
+	Action  string
+	Signer  string
+	ChainID string
+}
+
+func (f Filter) matches(a *ActionAccepted) bool {
+	if f.Action != "" && f.Action != a.Action {
+		return false
+	}
+	if f.Signer != "" && f.Signer != a.Signer {
+		return false
+	}
+	if f.ChainID != "" && f.ChainID != a.ChainID {
+		return false
+	}
+	return true
+}
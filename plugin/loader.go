@@ -0,0 +1,199 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/AnomalyFi/hypersdk/chain"
+	"github.com/AnomalyFi/hypersdk/codec"
+	"github.com/AnomalyFi/hypersdk/state"
+	"github.com/ava-labs/avalanchego/ids"
+	hplugin "github.com/hashicorp/go-plugin"
+	"go.uber.org/zap"
+)
+
+// reservedTypeIDStart is the first TypeID handed out to plugin-discovered
+// actions. IDs below it are reserved for actions built into this repo, so
+// a plugin can never accidentally shadow e.g. actions.Transfer.
+const reservedTypeIDStart = 200
+
+// Loaded is one discovered plugin, ready to be merged into
+// consts.ActionRegistry by the caller.
+type Loaded struct {
+	Name   string
+	TypeID uint8
+	client *hplugin.Client
+	action Action
+}
+
+// Action exposes the bytes-in/bytes-out contract for use by a
+// chain.Action adapter that the caller constructs (the adapter lives
+// outside this package to avoid an import cycle with chain/codec/state,
+// which consts already imports).
+func (l *Loaded) Action() Action { return l.action }
+
+// Close terminates the plugin subprocess. It should be called on VM
+// shutdown.
+func (l *Loaded) Close() { l.client.Kill() }
+
+// LoadDir launches every executable file in dir as a plugin subprocess,
+// dispenses its "action" implementation, and returns one Loaded entry per
+// binary. A directory that doesn't exist is treated as "no plugins" rather
+// than an error, since --plugin-dir is optional.
+func LoadDir(log Logger, dir string) ([]*Loaded, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading plugin dir %q: %w", dir, err)
+	}
+
+	var loaded []*Loaded
+	nextID := reservedTypeIDStart
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		info, err := entry.Info()
+		if err != nil || info.Mode()&0o111 == 0 {
+			continue // not executable, skip
+		}
+
+		c := hplugin.NewClient(&hplugin.ClientConfig{
+			HandshakeConfig: Handshake,
+			Plugins:         Map,
+			Cmd:             exec.Command(path),
+			AllowedProtocols: []hplugin.Protocol{
+				hplugin.ProtocolGRPC,
+			},
+		})
+		rpcClient, err := c.Client()
+		if err != nil {
+			c.Kill()
+			return nil, fmt.Errorf("launching plugin %q: %w", entry.Name(), err)
+		}
+		raw, err := rpcClient.Dispense(actionPluginName)
+		if err != nil {
+			c.Kill()
+			return nil, fmt.Errorf("dispensing plugin %q: %w", entry.Name(), err)
+		}
+		act, ok := raw.(Action)
+		if !ok {
+			c.Kill()
+			return nil, fmt.Errorf("plugin %q does not implement Action", entry.Name())
+		}
+
+		if nextID > 0xff {
+			c.Kill()
+			return nil, fmt.Errorf("too many plugins loaded from %q: ran out of reserved TypeIDs", dir)
+		}
+		id := uint8(nextID)
+		nextID++
+
+		log.Info("loaded action plugin", zap.String("name", entry.Name()), zap.Uint8("typeID", id))
+		loaded = append(loaded, &Loaded{Name: entry.Name(), TypeID: id, client: c, action: act})
+	}
+	return loaded, nil
+}
+
+// Logger is the minimal logging surface LoadDir needs; avalanchego's
+// logging.Logger (snow.Context.Log) and *zap.Logger both satisfy it.
+type Logger interface {
+	Info(msg string, fields ...zap.Field)
+}
+
+// maxActionBytes bounds how large a single plugin action's marshaled form
+// may be. It only needs to cover whatever the plugin author chooses to put
+// in their action, not anything this package interprets.
+const maxActionBytes = 64 * 1024
+
+// Adapt wraps a discovered plugin as a chain.Action so it can be merged
+// into consts.ActionRegistry, along with the unmarshal callback the
+// registry needs to turn wire bytes back into one. actionBytes is opaque
+// to this package: it's whatever the plugin's own Marshal produced, round-
+// tripped across the process boundary on every call so a plugin action's
+// behavior can depend on its own fields instead of always being the single
+// instance it was registered with.
+func Adapt(l *Loaded) (chain.Action, func(*codec.Packer) (chain.Action, error)) {
+	a := &adapter{l: l}
+	return a, a.unmarshal
+}
+
+type adapter struct {
+	l           *Loaded
+	actionBytes []byte
+}
+
+func (a *adapter) GetTypeID() uint8 { return a.l.TypeID }
+
+func (a *adapter) StateKeys(actor codec.Address, txID ids.ID) state.Keys {
+	raw := a.l.action.StateKeys(a.actionBytes, actor[:], txID[:])
+	keys := make(state.Keys, len(raw))
+	for _, k := range raw {
+		keys[string(k)] = state.All
+	}
+	return keys
+}
+
+func (a *adapter) MaxUnits(r chain.Rules) chain.Dimensions {
+	var d chain.Dimensions
+	raw := a.l.action.MaxUnits(a.actionBytes, r.Bytes())
+	copy(d[:], raw)
+	return d
+}
+
+// Execute ignores the state.Mutable the real chain.Action interface hands
+// it: Action's wire contract (see plugin.Action) has no RPC for a plugin to
+// read state at the keys it declared in StateKeys, or to return a mutation
+// for the host to apply, so a plugin action's Execute can only be a
+// function of its own actionBytes plus the rules/actor/tx arguments below.
+func (a *adapter) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	_ state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	txID ids.ID,
+) (bool, chain.Dimensions, []byte, error) {
+	success, unitsBytes, output, err := a.l.action.Execute(ctx, a.actionBytes, r.Bytes(), timestamp, actor[:], txID[:])
+	if err != nil {
+		return false, chain.Dimensions{}, nil, err
+	}
+	var d chain.Dimensions
+	copy(d[:], unitsBytes)
+	return success, d, output, nil
+}
+
+// Marshal and Size let a plugin action round-trip through the same
+// codec.Packer-based wire format every built-in action uses; Unmarshal
+// (via unmarshal below) is what ActionRegistry calls to reconstruct one.
+func (a *adapter) Marshal(p *codec.Packer) {
+	p.PackBytes(a.actionBytes)
+}
+
+func (a *adapter) Size() int {
+	return codec.BytesLen(a.actionBytes)
+}
+
+// unmarshal is Adapt's registry-facing callback: it produces a fresh
+// adapter carrying whatever actionBytes this particular tx packed, rather
+// than reusing the instance Adapt was called with.
+func (a *adapter) unmarshal(p *codec.Packer) (chain.Action, error) {
+	var raw []byte
+	p.UnpackBytes(maxActionBytes, true, &raw)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &adapter{l: a.l, actionBytes: raw}, nil
+}
@@ -0,0 +1,247 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package pb holds the gRPC contract shared by the host and plugin
+// binaries. In a full checkout with protoc available this would be
+// generated from plugin/proto/action.proto via protoc-gen-go and
+// protoc-gen-go-grpc, and the messages below would satisfy proto.Message
+// over the standard proto wire codec. Without protoc in this tree, the
+// messages are plain structs carried over a gob-based grpc.Codec
+// (gobCodec, registered by name below) instead: gob needs no generated
+// Marshal/Unmarshal/Reset/ProtoReflect boilerplate to round-trip arbitrary
+// Go structs, and grpc-go selects a registered codec by content-subtype
+// the same way it would select the proto codec, so ActionPluginClient/
+// ActionPluginServer dial and serve exactly as generated code would.
+package pb
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+// codecName is the content-subtype every ActionPlugin RPC is sent with, so
+// the server's codec lookup (driven off the incoming request's content-type
+// header) resolves to gobCodec instead of grpc-go's default proto codec.
+const codecName = "nodekit-seq-gob"
+
+func init() {
+	encoding.RegisterCodec(gobCodec{})
+}
+
+// gobCodec lets ActionPlugin's hand-written messages cross the gRPC
+// boundary without implementing proto.Message. encoding.RegisterCodec
+// makes it available process-wide; callers opt into it per-RPC via
+// grpc.CallContentSubtype(codecName) (see actionPluginClient below).
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+func (gobCodec) Name() string { return codecName }
+
+// TypeIDRequest is empty: TypeID takes no arguments, but the codec still
+// needs a concrete type to decode into on the server side.
+type TypeIDRequest struct{}
+
+// ExecuteRequest carries everything an out-of-process action needs to run
+// a single execution: the serialized action bytes produced by Marshal,
+// plus the ambient block/tx context it can't otherwise reach.
+type ExecuteRequest struct {
+	ActionBytes []byte
+	Rules       []byte // serialized chain.Rules
+	Timestamp   int64
+	Actor       []byte // codec.Address
+	TxID        []byte
+}
+
+type ExecuteResponse struct {
+	Success bool
+	Units   []byte // serialized chain.Dimensions
+	Output  []byte
+	Error   string
+}
+
+type StateKeysRequest struct {
+	ActionBytes []byte
+	Actor       []byte
+	TxID        []byte
+}
+
+type StateKeysResponse struct {
+	Keys [][]byte
+}
+
+type MaxUnitsRequest struct {
+	ActionBytes []byte
+	Rules       []byte
+}
+
+type MaxUnitsResponse struct {
+	Units []byte
+}
+
+type TypeIDResponse struct {
+	TypeID uint32
+}
+
+// ActionPluginClient is the host-side view of a plugin binary.
+type ActionPluginClient interface {
+	TypeID(ctx context.Context) (uint32, error)
+	Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error)
+	StateKeys(ctx context.Context, req *StateKeysRequest) (*StateKeysResponse, error)
+	MaxUnits(ctx context.Context, req *MaxUnitsRequest) (*MaxUnitsResponse, error)
+}
+
+// ActionPluginServer is implemented by the plugin binary and exposed over
+// the gRPC connection go-plugin brokers between the two processes.
+type ActionPluginServer interface {
+	TypeID(context.Context) (uint32, error)
+	Execute(context.Context, *ExecuteRequest) (*ExecuteResponse, error)
+	StateKeys(context.Context, *StateKeysRequest) (*StateKeysResponse, error)
+	MaxUnits(context.Context, *MaxUnitsRequest) (*MaxUnitsResponse, error)
+}
+
+// RegisterActionPluginServer and NewActionPluginClient wire the above
+// interfaces onto a *grpc.Server / *grpc.ClientConn using the service
+// descriptor protoc-gen-go-grpc would emit from action.proto. That
+// generated _grpc.pb.go is omitted from this snapshot; see
+// plugin/proto/action.proto for the source of truth.
+func RegisterActionPluginServer(s *grpc.Server, srv ActionPluginServer) {
+	s.RegisterService(&actionPluginServiceDesc, srv)
+}
+
+func NewActionPluginClient(cc *grpc.ClientConn) ActionPluginClient {
+	return &actionPluginClient{cc}
+}
+
+type actionPluginClient struct {
+	cc *grpc.ClientConn
+}
+
+// invoke is a small wrapper over cc.Invoke that adds the CallOption
+// selecting gobCodec; every method below goes through it so the subtype
+// never drifts from codecName.
+func (c *actionPluginClient) invoke(ctx context.Context, method string, req, out interface{}) error {
+	return c.cc.Invoke(ctx, method, req, out, grpc.CallContentSubtype(codecName))
+}
+
+func (c *actionPluginClient) TypeID(ctx context.Context) (uint32, error) {
+	out := new(TypeIDResponse)
+	if err := c.invoke(ctx, "/pb.ActionPlugin/TypeID", new(TypeIDRequest), out); err != nil {
+		return 0, err
+	}
+	return out.TypeID, nil
+}
+
+func (c *actionPluginClient) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	out := new(ExecuteResponse)
+	err := c.invoke(ctx, "/pb.ActionPlugin/Execute", req, out)
+	return out, err
+}
+
+func (c *actionPluginClient) StateKeys(ctx context.Context, req *StateKeysRequest) (*StateKeysResponse, error) {
+	out := new(StateKeysResponse)
+	err := c.invoke(ctx, "/pb.ActionPlugin/StateKeys", req, out)
+	return out, err
+}
+
+func (c *actionPluginClient) MaxUnits(ctx context.Context, req *MaxUnitsRequest) (*MaxUnitsResponse, error) {
+	out := new(MaxUnitsResponse)
+	err := c.invoke(ctx, "/pb.ActionPlugin/MaxUnits", req, out)
+	return out, err
+}
+
+func _ActionPlugin_TypeID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TypeIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	run := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		id, err := srv.(ActionPluginServer).TypeID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &TypeIDResponse{TypeID: id}, nil
+	}
+	if interceptor == nil {
+		return run(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/pb.ActionPlugin/TypeID"}
+	return interceptor(ctx, in, info, run)
+}
+
+func _ActionPlugin_Execute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	run := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActionPluginServer).Execute(ctx, req.(*ExecuteRequest))
+	}
+	if interceptor == nil {
+		return run(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/pb.ActionPlugin/Execute"}
+	return interceptor(ctx, in, info, run)
+}
+
+func _ActionPlugin_StateKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StateKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	run := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActionPluginServer).StateKeys(ctx, req.(*StateKeysRequest))
+	}
+	if interceptor == nil {
+		return run(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/pb.ActionPlugin/StateKeys"}
+	return interceptor(ctx, in, info, run)
+}
+
+func _ActionPlugin_MaxUnits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MaxUnitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	run := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActionPluginServer).MaxUnits(ctx, req.(*MaxUnitsRequest))
+	}
+	if interceptor == nil {
+		return run(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/pb.ActionPlugin/MaxUnits"}
+	return interceptor(ctx, in, info, run)
+}
+
+// actionPluginServiceDesc mirrors what protoc-gen-go-grpc generates for the
+// ActionPlugin service in action.proto: one MethodDesc per RPC, each
+// pointing at a _Handler func above that decodes the request, invokes the
+// matching ActionPluginServer method, and returns the response for the
+// transport to encode with gobCodec.
+var actionPluginServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.ActionPlugin",
+	HandlerType: (*ActionPluginServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "TypeID", Handler: _ActionPlugin_TypeID_Handler},
+		{MethodName: "Execute", Handler: _ActionPlugin_Execute_Handler},
+		{MethodName: "StateKeys", Handler: _ActionPlugin_StateKeys_Handler},
+		{MethodName: "MaxUnits", Handler: _ActionPlugin_MaxUnits_Handler},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "action.proto",
+}
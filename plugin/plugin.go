@@ -0,0 +1,170 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package plugin lets operators extend NodeKit-SEQ with additional
+// chain.Action (and, eventually, chain.Auth) implementations shipped as
+// out-of-process binaries, discovered from a --plugin-dir at VM start.
+// This mirrors how avalanchego's rpcchainvm attaches the EVM (and other
+// VMs) as a plugin rather than linking it in.
+package plugin
+
+import (
+	"context"
+
+	hplugin "github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+
+	"github.com/AnomalyFi/nodekit-seq/plugin/pb"
+)
+
+// Handshake is shared by the host and every plugin binary so a stray
+// process can't accidentally be dispensed as a NodeKit-SEQ action plugin.
+var Handshake = hplugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "NODEKIT_SEQ_PLUGIN",
+	MagicCookieValue: "action",
+}
+
+// actionPluginName is the key a plugin binary dispenses itself under.
+const actionPluginName = "action"
+
+// Action is the subset of chain.Action an out-of-process plugin must
+// implement. Bytes in, bytes out: everything that would otherwise be a
+// live object (ids.ID, codec.Address, chain.Rules, chain.Dimensions)
+// crosses the process boundary pre-serialized, matching the
+// ExecuteRequest/Response shape in plugin/proto/action.proto. actionBytes
+// is the wire encoding of the specific tx's action (see adapter.Marshal in
+// plugin/loader.go) so a plugin whose behavior depends on its own fields
+// isn't limited to the single instance it was registered with.
+//
+// Deliberately missing from this boundary: state.Mutable. A plugin action
+// can declare StateKeys, but Execute has no way to read the state at those
+// keys or to return a mutation for the host to apply — it can only compute
+// units/output from its own bytes plus the ambient rules/actor/tx
+// arguments. This is an intentional v1 scope cut, not an oversight: adding
+// real state access means either a second, host-side RPC a plugin calls
+// back into mid-Execute (via the same hplugin.GRPCBroker connection) to
+// Get/Has, plus a mutation set in ExecuteResponse for the host to apply
+// under the real state.Mutable, or accepting that plugin actions are
+// write-side no-ops by design. Until that lands, plugin actions should be
+// treated as stateless/read-only and documented to integrators as such.
+type Action interface {
+	TypeID() uint8
+	Execute(ctx context.Context, actionBytes, rules []byte, timestamp int64, actor, txID []byte) (success bool, units, output []byte, err error)
+	StateKeys(actionBytes, actor, txID []byte) [][]byte
+	MaxUnits(actionBytes, rules []byte) []byte
+}
+
+// GRPCPlugin adapts an Action to go-plugin's Plugin interface so it can be
+// served (by the plugin binary) or dispensed (by the host).
+type GRPCPlugin struct {
+	hplugin.NetRPCUnsupportedPlugin
+
+	// Impl is set by the plugin binary before calling Serve. It is nil on
+	// the host side, which only ever dials a client.
+	Impl Action
+}
+
+func (p *GRPCPlugin) GRPCServer(_ *hplugin.GRPCBroker, s *grpc.Server) error {
+	pb.RegisterActionPluginServer(s, &server{impl: p.Impl})
+	return nil
+}
+
+func (p *GRPCPlugin) GRPCClient(_ context.Context, _ *hplugin.GRPCBroker, cc *grpc.ClientConn) (interface{}, error) {
+	return &client{inner: pb.NewActionPluginClient(cc)}, nil
+}
+
+// Map is handed to hplugin.ClientConfig / hplugin.Serve on the respective
+// ends of the connection.
+var Map = map[string]hplugin.Plugin{
+	actionPluginName: &GRPCPlugin{},
+}
+
+// Serve is called from a plugin binary's main(): it blocks, answering gRPC
+// calls from the host by delegating to impl.
+func Serve(impl Action) {
+	hplugin.Serve(&hplugin.ServeConfig{
+		HandshakeConfig: Handshake,
+		Plugins: map[string]hplugin.Plugin{
+			actionPluginName: &GRPCPlugin{Impl: impl},
+		},
+		GRPCServer: hplugin.DefaultGRPCServer,
+	})
+}
+
+type server struct {
+	pb.ActionPluginServer
+	impl Action
+}
+
+func (s *server) TypeID(context.Context) (uint32, error) {
+	return uint32(s.impl.TypeID()), nil
+}
+
+func (s *server) Execute(_ context.Context, req *pb.ExecuteRequest) (*pb.ExecuteResponse, error) {
+	success, units, output, err := s.impl.Execute(context.Background(), req.ActionBytes, req.Rules, req.Timestamp, req.Actor, req.TxID)
+	resp := &pb.ExecuteResponse{Success: success, Units: units, Output: output}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	return resp, nil
+}
+
+func (s *server) StateKeys(_ context.Context, req *pb.StateKeysRequest) (*pb.StateKeysResponse, error) {
+	return &pb.StateKeysResponse{Keys: s.impl.StateKeys(req.ActionBytes, req.Actor, req.TxID)}, nil
+}
+
+func (s *server) MaxUnits(_ context.Context, req *pb.MaxUnitsRequest) (*pb.MaxUnitsResponse, error) {
+	return &pb.MaxUnitsResponse{Units: s.impl.MaxUnits(req.ActionBytes, req.Rules)}, nil
+}
+
+// client is the host-side handle to a dispensed plugin: it satisfies
+// Action by making a gRPC call per method.
+type client struct {
+	inner pb.ActionPluginClient
+}
+
+func (c *client) TypeID() uint8 {
+	id, err := c.inner.TypeID(context.Background())
+	if err != nil {
+		return 0
+	}
+	return uint8(id)
+}
+
+func (c *client) Execute(ctx context.Context, actionBytes, rules []byte, timestamp int64, actor, txID []byte) (bool, []byte, []byte, error) {
+	resp, err := c.inner.Execute(ctx, &pb.ExecuteRequest{
+		ActionBytes: actionBytes,
+		Rules:       rules,
+		Timestamp:   timestamp,
+		Actor:       actor,
+		TxID:        txID,
+	})
+	if err != nil {
+		return false, nil, nil, err
+	}
+	if resp.Error != "" {
+		return false, nil, nil, errString(resp.Error)
+	}
+	return resp.Success, resp.Units, resp.Output, nil
+}
+
+func (c *client) StateKeys(actionBytes, actor, txID []byte) [][]byte {
+	resp, err := c.inner.StateKeys(context.Background(), &pb.StateKeysRequest{ActionBytes: actionBytes, Actor: actor, TxID: txID})
+	if err != nil {
+		return nil
+	}
+	return resp.Keys
+}
+
+func (c *client) MaxUnits(actionBytes, rules []byte) []byte {
+	resp, err := c.inner.MaxUnits(context.Background(), &pb.MaxUnitsRequest{ActionBytes: actionBytes, Rules: rules})
+	if err != nil {
+		return nil
+	}
+	return resp.Units
+}
+
+type errString string
+
+func (e errString) Error() string { return string(e) }